@@ -22,58 +22,331 @@
 package fiberprometheus
 
 import (
+	"context"
+	"net/http"
+	"regexp"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gofiber/adaptor/v2"
 	"github.com/gofiber/fiber/v2"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
 )
 
+// pathCardinalityOther is the path label value used once MaxPathCardinality
+// distinct paths have been observed.
+const pathCardinalityOther = "<other>"
+
+var (
+	numericSegment = regexp.MustCompile(`^[0-9]+$`)
+	uuidSegment    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	ulidSegment    = regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+	hexSegment     = regexp.MustCompile(`^[0-9a-fA-F]{8,}$`)
+)
+
+// DefaultNormalizer collapses numeric, UUID, ULID and long hex path segments
+// down to ":id" so high-cardinality identifiers don't blow up the path label
+// when FullPaths is enabled. It's the default used when Config.Normalizer is
+// left unset.
+func DefaultNormalizer(ctx *fiber.Ctx) string {
+	segments := strings.Split(ctx.Path(), "/")
+	for i, seg := range segments {
+		if seg == "" {
+			continue
+		}
+		if numericSegment.MatchString(seg) || uuidSegment.MatchString(seg) || ulidSegment.MatchString(seg) || hexSegment.MatchString(seg) {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// defaultByteBuckets are used for the request/response size histograms when
+// the caller doesn't supply their own via Config. They span from a tiny
+// request (100B) up to a large upload/download (100MB) in powers of ~4.
+var defaultByteBuckets = []float64{
+	100, 400, 1600, 6400,
+	25600, 102400, 409600,
+	1638400, 6553600,
+	26214400, 104857600,
+}
+
+// defaultDurationBuckets are used for the request duration histogram when
+// the caller doesn't supply their own via Config.
+var defaultDurationBuckets = []float64{
+	0.000000001, // 1ns
+	0.000000002,
+	0.000000005,
+	0.00000001, // 10ns
+	0.00000002,
+	0.00000005,
+	0.0000001, // 100ns
+	0.0000002,
+	0.0000005,
+	0.000001, // 1µs
+	0.000002,
+	0.000005,
+	0.00001, // 10µs
+	0.00002,
+	0.00005,
+	0.0001, // 100µs
+	0.0002,
+	0.0005,
+	0.001, // 1ms
+	0.002,
+	0.005,
+	0.01, // 10ms
+	0.02,
+	0.05,
+	0.1, // 100 ms
+	0.2,
+	0.5,
+	1.0, // 1s
+	2.0,
+	5.0,
+	10.0, // 10s
+	15.0,
+	20.0,
+	30.0,
+}
+
+// NativeHistogram exposes the client_golang native (sparse) histogram knobs.
+// Setting NativeHistogramBucketFactor > 0 passes these knobs to the duration,
+// request size and response size histograms and leaves their classic Buckets
+// unset. client_golang builds native histogram buckets (Prometheus 2.40+),
+// letting scrapers negotiate exponential sparse buckets, only when the factor
+// is above 1; for a factor in (0, 1] it builds no native buckets and uses its
+// own prometheus.DefBuckets as the classic buckets.
+type NativeHistogram struct {
+	NativeHistogramBucketFactor     float64
+	NativeHistogramMaxBucketNumber  uint32
+	NativeHistogramMinResetDuration time.Duration
+	NativeHistogramZeroThreshold    float64
+}
+
+// applyBuckets sets the classic bucket boundaries on opts, falling back to
+// defaultBuckets when none are supplied. If nh.NativeHistogramBucketFactor is
+// above zero, the native histogram fields are populated instead and Buckets is
+// left unset; client_golang builds a sparse, exponentially-bucketed histogram
+// from them when the factor is above 1.
+func applyBuckets(opts *prometheus.HistogramOpts, buckets, defaultBuckets []float64, nh NativeHistogram) {
+	if nh.NativeHistogramBucketFactor > 0 {
+		opts.NativeHistogramBucketFactor = nh.NativeHistogramBucketFactor
+		opts.NativeHistogramMaxBucketNumber = nh.NativeHistogramMaxBucketNumber
+		opts.NativeHistogramMinResetDuration = nh.NativeHistogramMinResetDuration
+		opts.NativeHistogramZeroThreshold = nh.NativeHistogramZeroThreshold
+		return
+	}
+	if len(buckets) == 0 {
+		buckets = defaultBuckets
+	}
+	opts.Buckets = buckets
+}
+
 // FiberPrometheus ...
 type FiberPrometheus struct {
-	requestsTotal   *prometheus.CounterVec
-	requestDuration *prometheus.HistogramVec
-	requestInFlight *prometheus.GaugeVec
-	defaultURL      string
-	skipPaths       []string
-	fullPaths       bool
+	requestsTotal          *prometheus.CounterVec
+	requestDuration        *prometheus.HistogramVec
+	requestInFlight        *prometheus.GaugeVec
+	requestSize            *prometheus.HistogramVec
+	responseSize           *prometheus.HistogramVec
+	openConnections        prometheus.Gauge
+	pathCardinalityDropped prometheus.Counter
+	registry               prometheus.Registerer
+	namespace              string
+	subsystem              string
+	constLabels            prometheus.Labels
+	defaultURL             string
+	skipPaths              []string
+	fullPaths              bool
+	normalizer             func(*fiber.Ctx) string
+	maxPathCardinality     int
+	seenPaths              sync.Map
+	seenPathCount          int64
+	exemplars              ExemplarsConfig
+	pushGateway            PushGatewayConfig
+	server                 atomic.Pointer[http.Server]
+	pushCancel             context.CancelFunc
+	pushDone               chan struct{}
 }
 
 type Config struct {
-	registry    prometheus.Registerer
 	serviceName string
 	namespace   string
 	subsystem   string
 	labels      map[string]string
 	skipPaths   []string
-	fullPaths   bool
+
+	// Registry is the prometheus.Registerer metrics are registered against.
+	// Defaults to prometheus.DefaultRegisterer.
+	Registry prometheus.Registerer
+	// FullPaths reports the actual request path (run through Normalizer)
+	// instead of the matched route template.
+	FullPaths bool
+
+	// Buckets overrides the default bucket boundaries for the request
+	// duration histogram.
+	Buckets []float64
+	// RequestSizeBuckets overrides the default byte buckets used for the
+	// request size histogram.
+	RequestSizeBuckets []float64
+	// ResponseSizeBuckets overrides the default byte buckets used for the
+	// response size histogram.
+	ResponseSizeBuckets []float64
+
+	// NativeHistogram, when NativeHistogramBucketFactor is above 1, switches the
+	// duration/request-size/response-size histograms to the native (sparse)
+	// histogram format instead of the classic bucketed one. See NativeHistogram
+	// for factors in (0, 1].
+	NativeHistogram NativeHistogram
+
+	// Normalizer collapses a request's path into the value used for the path
+	// label. It only applies when FullPaths is enabled; defaults to
+	// DefaultNormalizer.
+	Normalizer func(*fiber.Ctx) string
+	// MaxPathCardinality caps the number of distinct path label values that
+	// will ever be reported; paths observed beyond the cap are reported as
+	// "<other>". Zero disables the cap.
+	MaxPathCardinality int
+
+	// Exemplars opts into attaching exemplars (trace/span ids) to the
+	// requests_total counter and request_duration_seconds histogram.
+	Exemplars ExemplarsConfig
+
+	// PushGateway, when URL is set, pushes the registry to a Prometheus
+	// Pushgateway on Interval. Intended for short-lived Fiber jobs (cron,
+	// batch) that can't be scraped.
+	PushGateway PushGatewayConfig
+}
+
+// PushGatewayConfig controls pushing metrics to a Prometheus Pushgateway; see
+// Config.PushGateway.
+type PushGatewayConfig struct {
+	URL       string
+	Job       string
+	Grouping  map[string]string
+	Interval  time.Duration
+	BasicAuth *PushGatewayBasicAuth
+}
+
+// PushGatewayBasicAuth carries Pushgateway basic-auth credentials.
+type PushGatewayBasicAuth struct {
+	Username string
+	Password string
+}
+
+// ExemplarsConfig controls exemplar attachment; see Config.Exemplars.
+type ExemplarsConfig struct {
+	// Enabled turns on exemplar support.
+	Enabled bool
+	// TraceHeaders are checked in order for a value to attach as the
+	// exemplar's trace_id label. Defaults to the W3C "traceparent" header,
+	// then "X-Request-Id".
+	TraceHeaders []string
+	// ExemplarLabelsFunc, if set, overrides TraceHeaders and builds the
+	// exemplar labels for a request directly.
+	ExemplarLabelsFunc func(*fiber.Ctx) prometheus.Labels
+}
+
+// defaultTraceHeaders are consulted in order when ExemplarsConfig.TraceHeaders
+// isn't set.
+var defaultTraceHeaders = []string{"traceparent", "X-Request-Id"}
+
+// exemplarLabels builds the exemplar labels for a request, preferring
+// ExemplarLabelsFunc when set and otherwise scanning TraceHeaders for the
+// first present value. The result is sanitized since it ultimately comes from
+// attacker-controlled request headers, and client_golang panics on oversized
+// or invalid-UTF-8 exemplar labels.
+func (e *ExemplarsConfig) exemplarLabels(ctx *fiber.Ctx) prometheus.Labels {
+	var labels prometheus.Labels
+	if e.ExemplarLabelsFunc != nil {
+		labels = e.ExemplarLabelsFunc(ctx)
+	} else {
+		headers := e.TraceHeaders
+		if len(headers) == 0 {
+			headers = defaultTraceHeaders
+		}
+		for _, header := range headers {
+			if v := ctx.Get(header); v != "" {
+				labels = prometheus.Labels{"trace_id": v}
+				break
+			}
+		}
+	}
+	return sanitizeExemplarLabels(labels)
+}
+
+// exemplarLabelName matches the label names client_golang accepts on exemplars.
+var exemplarLabelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// sanitizeExemplarLabels makes labels safe to pass to AddWithExemplar /
+// ObserveWithExemplar: client_golang panics if a label name is invalid (or
+// starts with the reserved "__" prefix), if a value isn't valid UTF-8, or if
+// the labels' combined rune count exceeds prometheus.ExemplarMaxRunes.
+// Invalid labels are dropped and values are truncated to fit the budget.
+func sanitizeExemplarLabels(labels prometheus.Labels) prometheus.Labels {
+	if len(labels) == 0 {
+		return nil
+	}
+	budget := prometheus.ExemplarMaxRunes
+	sanitized := make(prometheus.Labels, len(labels))
+	for k, v := range labels {
+		if !exemplarLabelName.MatchString(k) || strings.HasPrefix(k, "__") || !utf8.ValidString(v) || budget <= 0 {
+			continue
+		}
+		budget -= utf8.RuneCountInString(k)
+		if budget <= 0 {
+			continue
+		}
+		if n := utf8.RuneCountInString(v); n > budget {
+			v = string([]rune(v)[:budget])
+		}
+		budget -= utf8.RuneCountInString(v)
+		sanitized[k] = v
+	}
+	if len(sanitized) == 0 {
+		return nil
+	}
+	return sanitized
 }
 
 func (c *Config) fillDefaults() {
 	if c.serviceName == "" {
 		c.serviceName = "my-service"
 	}
-	if c.registry == nil {
-		c.registry = prometheus.DefaultRegisterer
+	if c.Registry == nil {
+		c.Registry = prometheus.DefaultRegisterer
 	}
 	if c.namespace == "" {
 		c.namespace = "http"
 	}
+	if len(c.RequestSizeBuckets) == 0 {
+		c.RequestSizeBuckets = defaultByteBuckets
+	}
+	if len(c.ResponseSizeBuckets) == 0 {
+		c.ResponseSizeBuckets = defaultByteBuckets
+	}
 }
 
-func create(registry prometheus.Registerer, serviceName, namespace, subsystem string, labels map[string]string, skipPaths []string, fullPaths bool) *FiberPrometheus {
+func create(cfg Config) *FiberPrometheus {
+	namespace, subsystem := cfg.namespace, cfg.subsystem
+
 	constLabels := make(prometheus.Labels)
-	if serviceName != "" {
-		constLabels["service"] = serviceName
+	if cfg.serviceName != "" {
+		constLabels["service"] = cfg.serviceName
 	}
-	for label, value := range labels {
+	for label, value := range cfg.labels {
 		constLabels[label] = value
 	}
 
-	counter := promauto.With(registry).NewCounterVec(
+	counter := promauto.With(cfg.Registry).NewCounterVec(
 		prometheus.CounterOpts{
 			Name:        prometheus.BuildFQName(namespace, subsystem, "requests_total"),
 			Help:        "Count all http requests by status code, method and path.",
@@ -81,75 +354,121 @@ func create(registry prometheus.Registerer, serviceName, namespace, subsystem st
 		},
 		[]string{"status_code", "method", "path"},
 	)
-	histogram := promauto.With(registry).NewHistogramVec(prometheus.HistogramOpts{
+
+	durationOpts := prometheus.HistogramOpts{
 		Name:        prometheus.BuildFQName(namespace, subsystem, "request_duration_seconds"),
 		Help:        "Duration of all HTTP requests by status code, method and path.",
 		ConstLabels: constLabels,
-		Buckets: []float64{
-			0.000000001, // 1ns
-			0.000000002,
-			0.000000005,
-			0.00000001, // 10ns
-			0.00000002,
-			0.00000005,
-			0.0000001, // 100ns
-			0.0000002,
-			0.0000005,
-			0.000001, // 1µs
-			0.000002,
-			0.000005,
-			0.00001, // 10µs
-			0.00002,
-			0.00005,
-			0.0001, // 100µs
-			0.0002,
-			0.0005,
-			0.001, // 1ms
-			0.002,
-			0.005,
-			0.01, // 10ms
-			0.02,
-			0.05,
-			0.1, // 100 ms
-			0.2,
-			0.5,
-			1.0, // 1s
-			2.0,
-			5.0,
-			10.0, // 10s
-			15.0,
-			20.0,
-			30.0,
-		},
-	},
-		[]string{"status_code", "method", "path"},
-	)
+	}
+	applyBuckets(&durationOpts, cfg.Buckets, defaultDurationBuckets, cfg.NativeHistogram)
+	histogram := promauto.With(cfg.Registry).NewHistogramVec(durationOpts, []string{"status_code", "method", "path"})
 
-	gauge := promauto.With(registry).NewGaugeVec(prometheus.GaugeOpts{
+	gauge := promauto.With(cfg.Registry).NewGaugeVec(prometheus.GaugeOpts{
 		Name:        prometheus.BuildFQName(namespace, subsystem, "requests_in_progress_total"),
 		Help:        "All the requests in progress",
 		ConstLabels: constLabels,
 	}, []string{"method"})
 
-	return &FiberPrometheus{
-		requestsTotal:   counter,
-		requestDuration: histogram,
-		requestInFlight: gauge,
-		defaultURL:      "/metrics",
-		skipPaths:       skipPaths,
-		fullPaths:       fullPaths,
+	requestSizeOpts := prometheus.HistogramOpts{
+		Name:        prometheus.BuildFQName(namespace, subsystem, "request_size_bytes"),
+		Help:        "Size of all HTTP requests by status code, method and path.",
+		ConstLabels: constLabels,
 	}
+	applyBuckets(&requestSizeOpts, cfg.RequestSizeBuckets, defaultByteBuckets, cfg.NativeHistogram)
+	requestSize := promauto.With(cfg.Registry).NewHistogramVec(requestSizeOpts, []string{"status_code", "method", "path"})
+
+	responseSizeOpts := prometheus.HistogramOpts{
+		Name:        prometheus.BuildFQName(namespace, subsystem, "response_size_bytes"),
+		Help:        "Size of all HTTP responses by status code, method and path.",
+		ConstLabels: constLabels,
+	}
+	applyBuckets(&responseSizeOpts, cfg.ResponseSizeBuckets, defaultByteBuckets, cfg.NativeHistogram)
+	responseSize := promauto.With(cfg.Registry).NewHistogramVec(responseSizeOpts, []string{"status_code", "method", "path"})
+
+	openConnections := promauto.With(cfg.Registry).NewGauge(prometheus.GaugeOpts{
+		Name:        prometheus.BuildFQName(namespace, subsystem, "open_connections"),
+		Help:        "Number of requests currently being served, regardless of route match.",
+		ConstLabels: constLabels,
+	})
+
+	pathCardinalityDropped := promauto.With(cfg.Registry).NewCounter(prometheus.CounterOpts{
+		Name:        prometheus.BuildFQName(namespace, subsystem, "path_cardinality_dropped_total"),
+		Help:        "Count of requests whose path was bucketed into \"<other>\" after MaxPathCardinality distinct paths were observed.",
+		ConstLabels: constLabels,
+	})
+
+	normalizer := cfg.Normalizer
+	if normalizer == nil {
+		normalizer = DefaultNormalizer
+	}
+
+	ps := &FiberPrometheus{
+		requestsTotal:          counter,
+		requestDuration:        histogram,
+		requestInFlight:        gauge,
+		requestSize:            requestSize,
+		responseSize:           responseSize,
+		openConnections:        openConnections,
+		pathCardinalityDropped: pathCardinalityDropped,
+		registry:               cfg.Registry,
+		namespace:              namespace,
+		subsystem:              subsystem,
+		constLabels:            constLabels,
+		defaultURL:             "/metrics",
+		skipPaths:              cfg.skipPaths,
+		fullPaths:              cfg.FullPaths,
+		normalizer:             normalizer,
+		maxPathCardinality:     cfg.MaxPathCardinality,
+		exemplars:              cfg.Exemplars,
+		pushGateway:            cfg.PushGateway,
+	}
+
+	if ps.pushGateway.URL != "" {
+		ps.startPushGateway()
+	}
+
+	return ps
+}
+
+// guardPath enforces maxPathCardinality: once that many distinct paths have
+// been observed, further novel paths are bucketed into pathCardinalityOther.
+// Observed paths are tracked in a concurrent set guarded by an atomic
+// counter, keeping the hot path (an already-seen path) lock-free.
+func (ps *FiberPrometheus) guardPath(path string) string {
+	if ps.maxPathCardinality <= 0 {
+		return path
+	}
+	if _, ok := ps.seenPaths.Load(path); ok {
+		return path
+	}
+	// Reserve a slot before storing so concurrent novel paths can't push the
+	// count past maxPathCardinality; the slot is released if another request
+	// stored the same path first.
+	for {
+		n := atomic.LoadInt64(&ps.seenPathCount)
+		if n >= int64(ps.maxPathCardinality) {
+			ps.pathCardinalityDropped.Inc()
+			return pathCardinalityOther
+		}
+		if atomic.CompareAndSwapInt64(&ps.seenPathCount, n, n+1) {
+			break
+		}
+	}
+	if _, loaded := ps.seenPaths.LoadOrStore(path, struct{}{}); loaded {
+		atomic.AddInt64(&ps.seenPathCount, -1)
+	}
+	return path
 }
 
 // New creates a new instance of FiberPrometheus middleware
 // serviceName is available as a const label
 func New(serviceName string) *FiberPrometheus {
-	return create(prometheus.DefaultRegisterer, serviceName, "http", "", nil, nil, false)
+	return create(Config{Registry: prometheus.DefaultRegisterer, serviceName: serviceName, namespace: "http"})
 }
 
 func NewFromConfig(config Config) *FiberPrometheus {
 	config.fillDefaults()
-	return create(config.registry, config.serviceName, config.namespace, config.subsystem, config.labels, config.skipPaths, config.fullPaths)
+	return create(config)
 }
 
 // NewWith creates a new instance of FiberPrometheus middleware but with an ability
@@ -160,7 +479,7 @@ func NewFromConfig(config Config) *FiberPrometheus {
 // For e.g. namespace = "my_app", subsystem = "http" then metrics would be
 // `my_app_http_requests_total{...,service= "serviceName"}`
 func NewWith(serviceName, namespace, subsystem string) *FiberPrometheus {
-	return create(prometheus.DefaultRegisterer, serviceName, namespace, subsystem, nil, nil, false)
+	return create(Config{Registry: prometheus.DefaultRegisterer, serviceName: serviceName, namespace: namespace, subsystem: subsystem})
 }
 
 // NewWithLabels creates a new instance of FiberPrometheus middleware but with an ability
@@ -172,7 +491,7 @@ func NewWith(serviceName, namespace, subsystem string) *FiberPrometheus {
 // then then metrics would become
 // `my_app_http_requests_total{...,key1= "value1", key2= "value2" }`
 func NewWithLabels(labels map[string]string, namespace, subsystem string) *FiberPrometheus {
-	return create(prometheus.DefaultRegisterer, "", namespace, subsystem, labels, nil, false)
+	return create(Config{Registry: prometheus.DefaultRegisterer, namespace: namespace, subsystem: subsystem, labels: labels})
 }
 
 // NewWithRegistry creates a new instance of FiberPrometheus middleware but with an ability
@@ -184,23 +503,152 @@ func NewWithLabels(labels map[string]string, namespace, subsystem string) *Fiber
 // then then metrics would become
 // `my_app_http_requests_total{...,key1= "value1", key2= "value2" }`
 func NewWithRegistry(registry prometheus.Registerer, serviceName, namespace, subsystem string, labels map[string]string) *FiberPrometheus {
-	return create(registry, serviceName, namespace, subsystem, labels, nil, false)
+	return create(Config{Registry: registry, serviceName: serviceName, namespace: namespace, subsystem: subsystem, labels: labels})
+}
+
+// metricsHandler builds the promhttp handler for the middleware's registry,
+// enabling OpenMetrics (and therefore exemplars) when the registry is also a
+// Gatherer.
+func (ps *FiberPrometheus) metricsHandler() http.Handler {
+	if gatherer, ok := ps.registry.(prometheus.Gatherer); ok {
+		return promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{EnableOpenMetrics: true})
+	}
+	return promhttp.Handler()
 }
 
 // RegisterAt will register the prometheus handler at a given URL
 func (ps *FiberPrometheus) RegisterAt(app fiber.Router, url string, handlers ...fiber.Handler) {
 	ps.defaultURL = url
 
-	h := append(handlers, adaptor.HTTPHandler(promhttp.Handler()))
+	h := append(handlers, adaptor.HTTPHandler(ps.metricsHandler()))
 	app.Get(ps.defaultURL, h...)
 }
 
+// ListenAndServe starts a standalone HTTP server exposing the metrics
+// endpoint, separate from the application's own Fiber listener, so operators
+// can bind metrics to e.g. 127.0.0.1:9090 while the app listens publicly.
+func (ps *FiberPrometheus) ListenAndServe(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle(ps.defaultURL, ps.metricsHandler())
+
+	server := &http.Server{Addr: addr, Handler: mux}
+	ps.server.Store(server)
+	return server.ListenAndServe()
+}
+
+// Shutdown gracefully stops the standalone metrics server started by
+// ListenAndServe, if any, and the Pushgateway goroutine started for
+// Config.PushGateway, if any.
+func (ps *FiberPrometheus) Shutdown(ctx context.Context) error {
+	if ps.pushCancel != nil {
+		ps.pushCancel()
+		<-ps.pushDone
+	}
+	if server := ps.server.Load(); server != nil {
+		return server.Shutdown(ctx)
+	}
+	return nil
+}
+
+// startPushGateway launches the background goroutine that periodically
+// pushes the registry to Config.PushGateway.URL until Shutdown is called.
+func (ps *FiberPrometheus) startPushGateway() {
+	gatherer, ok := ps.registry.(prometheus.Gatherer)
+	if !ok {
+		panic("fiberprometheus: Config.PushGateway requires a registry that implements prometheus.Gatherer (e.g. prometheus.NewRegistry())")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ps.pushCancel = cancel
+	ps.pushDone = make(chan struct{})
+
+	pusher := push.New(ps.pushGateway.URL, ps.pushGateway.Job).Gatherer(gatherer)
+	for name, value := range ps.pushGateway.Grouping {
+		pusher = pusher.Grouping(name, value)
+	}
+	if ps.pushGateway.BasicAuth != nil {
+		pusher = pusher.BasicAuth(ps.pushGateway.BasicAuth.Username, ps.pushGateway.BasicAuth.Password)
+	}
+
+	interval := ps.pushGateway.Interval
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+
+	go func() {
+		defer close(ps.pushDone)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = pusher.Push()
+			}
+		}
+	}()
+}
+
+// Registry returns the prometheus.Registerer backing this middleware.
+func (ps *FiberPrometheus) Registry() prometheus.Registerer {
+	return ps.registry
+}
+
+// Gatherer returns the prometheus.Gatherer backing this middleware, if any.
+func (ps *FiberPrometheus) Gatherer() prometheus.Gatherer {
+	gatherer, _ := ps.registry.(prometheus.Gatherer)
+	return gatherer
+}
+
+func (ps *FiberPrometheus) mergeConstLabels(extra prometheus.Labels) prometheus.Labels {
+	merged := make(prometheus.Labels, len(ps.constLabels)+len(extra))
+	for k, v := range ps.constLabels {
+		merged[k] = v
+	}
+	for k, v := range extra {
+		merged[k] = v
+	}
+	return merged
+}
+
+// NewCounter registers a CounterVec against the middleware's registry.
+func (ps *FiberPrometheus) NewCounter(opts prometheus.CounterOpts, labels ...string) *prometheus.CounterVec {
+	opts.Name = prometheus.BuildFQName(ps.namespace, ps.subsystem, opts.Name)
+	opts.ConstLabels = ps.mergeConstLabels(opts.ConstLabels)
+	return promauto.With(ps.registry).NewCounterVec(opts, labels)
+}
+
+// NewHistogram registers a HistogramVec against the middleware's registry.
+func (ps *FiberPrometheus) NewHistogram(opts prometheus.HistogramOpts, labels ...string) *prometheus.HistogramVec {
+	opts.Name = prometheus.BuildFQName(ps.namespace, ps.subsystem, opts.Name)
+	opts.ConstLabels = ps.mergeConstLabels(opts.ConstLabels)
+	return promauto.With(ps.registry).NewHistogramVec(opts, labels)
+}
+
+// NewGauge registers a GaugeVec against the middleware's registry.
+func (ps *FiberPrometheus) NewGauge(opts prometheus.GaugeOpts, labels ...string) *prometheus.GaugeVec {
+	opts.Name = prometheus.BuildFQName(ps.namespace, ps.subsystem, opts.Name)
+	opts.ConstLabels = ps.mergeConstLabels(opts.ConstLabels)
+	return promauto.With(ps.registry).NewGaugeVec(opts, labels)
+}
+
+// NewSummary registers a SummaryVec against the middleware's registry.
+func (ps *FiberPrometheus) NewSummary(opts prometheus.SummaryOpts, labels ...string) *prometheus.SummaryVec {
+	opts.Name = prometheus.BuildFQName(ps.namespace, ps.subsystem, opts.Name)
+	opts.ConstLabels = ps.mergeConstLabels(opts.ConstLabels)
+	return promauto.With(ps.registry).NewSummaryVec(opts, labels)
+}
+
 // Middleware is the actual default middleware implementation
 func (ps *FiberPrometheus) Middleware(ctx *fiber.Ctx) error {
 
 	start := time.Now()
 	method := ctx.Route().Method
 
+	ps.openConnections.Inc()
+	defer ps.openConnections.Dec()
+
 	if ctx.Route().Path == ps.defaultURL {
 		return ctx.Next()
 	}
@@ -210,6 +658,11 @@ func (ps *FiberPrometheus) Middleware(ctx *fiber.Ctx) error {
 		ps.requestInFlight.WithLabelValues(method).Dec()
 	}()
 
+	requestSize := float64(ctx.Request().Header.ContentLength())
+	if requestSize < 0 {
+		requestSize = float64(len(ctx.Body()))
+	}
+
 	err := ctx.Next()
 	// initialize with default error code
 	// https://docs.gofiber.io/guide/error-handling
@@ -225,15 +678,25 @@ func (ps *FiberPrometheus) Middleware(ctx *fiber.Ctx) error {
 
 	var path string
 	if ps.fullPaths {
-		path = ctx.Path()
+		path = ps.normalizer(ctx)
 	} else {
 		path = ctx.Route().Path
 	}
+	path = ps.guardPath(path)
 	statusCode := strconv.Itoa(status)
-	ps.requestsTotal.WithLabelValues(statusCode, method, path).Inc()
-
 	elapsed := float64(time.Since(start).Nanoseconds()) / 1e9
-	ps.requestDuration.WithLabelValues(statusCode, method, path).Observe(elapsed)
+
+	if ps.exemplars.Enabled {
+		exemplar := ps.exemplars.exemplarLabels(ctx)
+		ps.requestsTotal.WithLabelValues(statusCode, method, path).(prometheus.ExemplarAdder).AddWithExemplar(1, exemplar)
+		ps.requestDuration.WithLabelValues(statusCode, method, path).(prometheus.ExemplarObserver).ObserveWithExemplar(elapsed, exemplar)
+	} else {
+		ps.requestsTotal.WithLabelValues(statusCode, method, path).Inc()
+		ps.requestDuration.WithLabelValues(statusCode, method, path).Observe(elapsed)
+	}
+
+	ps.requestSize.WithLabelValues(statusCode, method, path).Observe(requestSize)
+	ps.responseSize.WithLabelValues(statusCode, method, path).Observe(float64(len(ctx.Response().Body())))
 
 	return err
 }